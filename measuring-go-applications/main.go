@@ -12,27 +12,26 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/XSAM/otelsql"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	logglobal "go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
-	"go.opentelemetry.io/otel/propagation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/exemplar"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/tmeire/blog-snippets/telemetry"
+	"github.com/tmeire/blog-snippets/telemetry/dbotel"
+	"github.com/tmeire/blog-snippets/telemetry/logging"
+
 	_ "github.com/mattn/go-sqlite3"
 )
 
-var db *sql.DB
+var db *dbotel.DB
 
 type User struct {
 	ID       int    `json:"id"`
@@ -41,53 +40,11 @@ type User struct {
 	password []byte
 }
 
-func setupOpenTelemetry(ctx context.Context) func(context.Context) error {
-	res, _ := resource.Merge(resource.Default(), resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName("users-api"),
-		semconv.ServiceVersion("1.4.2"),
-		attribute.String("deployment.environment", "prod"),
-	))
-
-	// ---- Tracing
-	exporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint("localhost:4317"),
-		otlptracegrpc.WithInsecure(),
-	)
-	if err != nil {
-		log.Fatalf("Failed to create exporter: %v", err)
-	}
-
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(res),
-		sdktrace.WithBatcher(exporter),
-	)
-	otel.SetTracerProvider(tp)
-
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
-	// ---- Metrics
-	exp, err := otlpmetricgrpc.New(ctx,
-		otlpmetricgrpc.WithEndpoint("localhost:4317"),
-		otlpmetricgrpc.WithInsecure(),
-	) // or use Prometheus exporter
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	reader := sdkmetric.NewPeriodicReader(exp)
-
-	// Register a view to:
-	//	- collect the http.server.duration metric with a different histogram aggregation that may be
-	//	  more suitable for this use case. The default bucket boundaries are:
-	//	  0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000
-	//	- filter out the high-cardinality "user_id" attribute from the http.server.request.duration metric.
-
-	view := sdkmetric.NewView(
+// metricsView collects http.server.request.duration with a histogram
+// aggregation tighter than the default boundaries and drops the
+// high-cardinality "user_id" attribute before export.
+func metricsView() sdkmetric.View {
+	return sdkmetric.NewView(
 		sdkmetric.Instrument{Name: "http.server.request.duration"},
 		sdkmetric.Stream{
 			Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
@@ -96,62 +53,16 @@ func setupOpenTelemetry(ctx context.Context) func(context.Context) error {
 			AttributeFilter: func(k attribute.KeyValue) bool { return k.Key != "user_id" },
 		},
 	)
-
-	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(reader),
-		sdkmetric.WithResource(res),
-		sdkmetric.WithView(view),
-		sdkmetric.WithExemplarFilter(exemplar.AlwaysOnFilter),
-	)
-	otel.SetMeterProvider(mp)
-
-	err = runtime.Start(
-		runtime.WithMeterProvider(mp),
-		// Collect memory metrics every second, the default is every 15s. This is included as an example in case you
-		// would ever need this level of granularity, but it is NOT RECOMMENDED to do this in production environments.
-		// The underlying system calls are expensive and could negatively impact your application performance.
-		runtime.WithMinimumReadMemStatsInterval(time.Second),
-	)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// ---- Cleanup of both the metric and trace providers
-	return func(ctx context.Context) error {
-		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-
-		var err error
-
-		if tpErr := tp.Shutdown(ctx); tpErr != nil {
-			slog.Warn("Failed to shut down tracer provider", "error", tpErr)
-			err = errors.Join(err, tpErr)
-		}
-		if mpErr := mp.Shutdown(ctx); mpErr != nil {
-			slog.Warn("Failed to shut down metric provider", "error", mpErr)
-			err = errors.Join(err, mpErr)
-		}
-		return err
-	}
 }
 
 func initDB() {
-	// Register the sqlite driver with otelsql
-	driverName, err := otelsql.Register("sqlite3",
-		otelsql.WithAttributes(semconv.DBSystemSqlite),
-		otelsql.WithSpanOptions(otelsql.SpanOptions{
-			Ping:     true,
-			RowsNext: true,
-		}),
-	)
+	var err error
+	db, err = dbotel.Open("sqlite3", "users.db", dbotel.Options{
+		DBSystem:           semconv.DBSystemSqlite,
+		SlowQueryThreshold: 100 * time.Millisecond,
+	})
 	if err != nil {
-		log.Fatalf("Failed to register otelsql driver: %v", err)
-	}
-
-	// Open a database connection using the instrumented driver
-	db, err = sql.Open(driverName, "users.db")
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to open database: %v", err)
 	}
 
 	if err = db.Ping(); err != nil {
@@ -175,6 +86,7 @@ func signinHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user from database
 	user, err := getUserFromDB(ctx, userID)
 	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "user_id", userID, "error", err)
 		http.Error(w, "Failed to get user", http.StatusInternalServerError)
 		return
 	}
@@ -191,6 +103,7 @@ func signinHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
+		slog.WarnContext(ctx, "Authentication failed", "user_id", userID, "error", err)
 		http.Error(w, "Not authenticated", http.StatusUnauthorized)
 		return
 	}
@@ -211,6 +124,7 @@ func getUserHandler(w http.ResponseWriter, r *http.Request) {
 	// Get user from database
 	user, err := getUserFromDB(ctx, userID)
 	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get user", "user_id", userID, "error", err)
 		http.Error(w, "Failed to get user", http.StatusInternalServerError)
 		return
 	}
@@ -254,12 +168,38 @@ func getUserFromDB(ctx context.Context, userID string) (*User, error) {
 func main() {
 	ctx := context.Background()
 
-	cleanup := setupOpenTelemetry(ctx)
+	opts := telemetry.DefaultOptions()
+	opts.ServiceName = "users-api"
+	opts.ServiceVersion = "1.4.2"
+	opts.Environment = "prod"
+	opts.Views = []sdkmetric.View{metricsView()}
+	opts.LogExporter = logging.ExporterOTLPGRPC
+	// Every measurement here is worth an exemplar, not just ones tied to a
+	// sampled trace - this example exists to show exemplars in the first place.
+	opts.ExemplarFilter = exemplar.AlwaysOnFilter
+
+	shutdown, err := telemetry.Setup(ctx, opts)
+	if err != nil {
+		log.Fatalf("Failed to set up telemetry: %v", err)
+	}
 	defer func() {
-		err := cleanup(ctx)
-		log.Fatal(err)
+		if err := shutdown(ctx); err != nil {
+			log.Fatalf("Failed to shut down telemetry: %v", err)
+		}
 	}()
 
+	slog.SetDefault(slog.New(logging.NewHandler(logglobal.GetLoggerProvider(), "users-api")))
+
+	if err := runtime.Start(
+		runtime.WithMeterProvider(otel.GetMeterProvider()),
+		// Collect memory metrics every second, the default is every 15s. This is included as an example in case you
+		// would ever need this level of granularity, but it is NOT RECOMMENDED to do this in production environments.
+		// The underlying system calls are expensive and could negatively impact your application performance.
+		runtime.WithMinimumReadMemStatsInterval(time.Second),
+	); err != nil {
+		log.Fatal(err)
+	}
+
 	hashLatency, _ = otel.GetMeterProvider().Meter("users-api").Float64Histogram("user.auth.password_check.latency") // seconds
 	hashError, _ = otel.GetMeterProvider().Meter("users-api").Int64Counter("user.auth.password_check.errors")
 
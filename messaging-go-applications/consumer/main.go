@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tmeire/blog-snippets/telemetry"
+)
+
+const topic = "orders.created"
+
+var tracer trace.Tracer
+var processLatency metric.Float64Histogram
+
+// saramaHeaderCarrier adapts a sarama message's headers to
+// propagation.TextMapCarrier so the trace context the producer injected
+// can be extracted and used to continue the trace.
+type saramaHeaderCarrier struct {
+	headers []*sarama.RecordHeader
+}
+
+func (c saramaHeaderCarrier) Get(key string) string {
+	for _, h := range c.headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c saramaHeaderCarrier) Set(string, string) {
+	// Consumers only extract; nothing to propagate back onto the message.
+}
+
+func (c saramaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(c.headers))
+	for i, h := range c.headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+// consumerHandler implements sarama.ConsumerGroupHandler, continuing the
+// producer's trace for every message it processes.
+type consumerHandler struct{}
+
+func (consumerHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (consumerHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		processMessage(session.Context(), msg)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+func processMessage(ctx context.Context, msg *sarama.ConsumerMessage) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, saramaHeaderCarrier{headers: msg.Headers})
+
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("%s process", msg.Topic),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(msg.Topic),
+			semconv.MessagingOperationReceive,
+			semconv.MessagingKafkaMessageKey(string(msg.Key)),
+			semconv.MessagingKafkaDestinationPartition(int(msg.Partition)),
+			semconv.MessagingKafkaMessageOffset(int(msg.Offset)),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	// Your message handling code here.
+	err := handleOrderCreated(ctx, msg.Value)
+	processLatency.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("messaging.destination.name", msg.Topic),
+		attribute.Bool("success", err == nil),
+	))
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+func handleOrderCreated(_ context.Context, payload []byte) error {
+	log.Printf("Processing order: %s", payload)
+	return nil
+}
+
+func main() {
+	ctx := context.Background()
+
+	opts := telemetry.DefaultOptions()
+	opts.ServiceName = "orders-consumer"
+	opts.ServiceVersion = "1.0.0"
+
+	shutdown, err := telemetry.Setup(ctx, opts)
+	if err != nil {
+		log.Fatalf("Failed to set up telemetry: %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Fatalf("Failed to shut down telemetry: %v", err)
+		}
+	}()
+
+	tracer = otel.Tracer("orders-consumer")
+	processLatency, err = otel.GetMeterProvider().Meter("orders-consumer").Float64Histogram("messaging.client.process.duration") // seconds
+	if err != nil {
+		log.Fatalf("Failed to create process latency histogram: %v", err)
+	}
+
+	cfg := sarama.NewConfig()
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	group, err := sarama.NewConsumerGroup([]string{"localhost:9092"}, "orders-consumer", cfg)
+	if err != nil {
+		log.Fatalf("Failed to create consumer group: %v", err)
+	}
+	defer group.Close()
+
+	for {
+		if err := group.Consume(ctx, []string{topic}, consumerHandler{}); err != nil {
+			log.Fatalf("Consumer group session ended: %v", err)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
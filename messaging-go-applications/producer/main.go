@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tmeire/blog-snippets/telemetry"
+)
+
+const topic = "orders.created"
+
+var tracer trace.Tracer
+var publishLatency metric.Float64Histogram
+
+// saramaHeaderCarrier adapts a sarama message's headers to
+// propagation.TextMapCarrier so the active trace context can be injected
+// into it on publish.
+type saramaHeaderCarrier struct {
+	headers *[]sarama.RecordHeader
+}
+
+func (c saramaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c saramaHeaderCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if string(h.Key) == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c saramaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+// publishOrderCreated publishes a message to topic, injecting the current
+// trace context into its headers so the consumer can continue the trace,
+// and records messaging.* span attributes and a per-topic publish latency
+// histogram.
+func publishOrderCreated(ctx context.Context, producer sarama.SyncProducer, key, value string) error {
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("%s publish", topic),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationPublish,
+		),
+	)
+	defer span.End()
+
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.StringEncoder(value),
+	}
+	otel.GetTextMapPropagator().Inject(ctx, saramaHeaderCarrier{headers: &msg.Headers})
+
+	start := time.Now()
+	partition, offset, err := producer.SendMessage(msg)
+	publishLatency.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("messaging.destination.name", topic),
+		attribute.Bool("success", err == nil),
+	))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetAttributes(
+		semconv.MessagingKafkaMessageKey(key),
+		semconv.MessagingKafkaDestinationPartition(int(partition)),
+		semconv.MessagingKafkaMessageOffset(int(offset)),
+	)
+	return nil
+}
+
+func main() {
+	ctx := context.Background()
+
+	opts := telemetry.DefaultOptions()
+	opts.ServiceName = "orders-producer"
+	opts.ServiceVersion = "1.0.0"
+
+	shutdown, err := telemetry.Setup(ctx, opts)
+	if err != nil {
+		log.Fatalf("Failed to set up telemetry: %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Fatalf("Failed to shut down telemetry: %v", err)
+		}
+	}()
+
+	tracer = otel.Tracer("orders-producer")
+	publishLatency, err = otel.GetMeterProvider().Meter("orders-producer").Float64Histogram("messaging.client.publish.duration") // seconds
+	if err != nil {
+		log.Fatalf("Failed to create publish latency histogram: %v", err)
+	}
+
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer([]string{"localhost:9092"}, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create producer: %v", err)
+	}
+	defer producer.Close()
+
+	if err := publishOrderCreated(ctx, producer, "order-42", `{"order_id":42}`); err != nil {
+		log.Fatalf("Failed to publish message: %v", err)
+	}
+
+	log.Println("Published order-42")
+}
@@ -0,0 +1,141 @@
+// Package dbotel wraps github.com/XSAM/otelsql with the pieces every
+// example in this repo was hand-rolling around its own initDB: sanitized
+// SQL statements on spans, a slow-query threshold that raises a span event
+// and a counter, and a pluggable QueryHook so callers can attach their own
+// per-query attributes (tenant id, shard, ...).
+package dbotel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Options configures Open.
+type Options struct {
+	// DBSystem identifies the backend, e.g. semconv.DBSystemSqlite.
+	DBSystem attribute.KeyValue
+
+	// SlowQueryThreshold is the query duration at or above which a "slow_query"
+	// span event is recorded and the db.client.slow_queries counter is
+	// incremented. Zero disables slow-query tracking.
+	SlowQueryThreshold time.Duration
+
+	// Hooks run around every query issued through DB, in order.
+	Hooks []QueryHook
+
+	// MeterProvider defaults to otel.GetMeterProvider() when nil.
+	MeterProvider metric.MeterProvider
+}
+
+// DB wraps *sql.DB with the behavior described in Options. Use it the same
+// way you'd use *sql.DB; QueryContext/ExecContext/QueryRowContext run the
+// configured hooks and slow-query check around the otelsql-instrumented
+// call.
+type DB struct {
+	*sql.DB
+
+	opts        Options
+	slowQueries metric.Int64Counter
+}
+
+// Open registers driverName with otelsql (statement sanitization plus the
+// standard Ping/RowsNext span options), opens dsn through it, registers the
+// db.client.connections.* gauges via otelsql.RegisterDBStatsMetrics, and
+// returns a DB ready to use.
+func Open(driverName, dsn string, opts Options) (*DB, error) {
+	otelDriverName, err := otelsql.Register(driverName,
+		otelsql.WithAttributes(opts.DBSystem),
+		otelsql.WithAttributesGetter(attributesGetter),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			Ping:     true,
+			RowsNext: true,
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("dbotel: register otelsql driver: %w", err)
+	}
+
+	sqlDB, err := sql.Open(otelDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("dbotel: open database: %w", err)
+	}
+
+	if _, err := otelsql.RegisterDBStatsMetrics(sqlDB, otelsql.WithAttributes(opts.DBSystem)); err != nil {
+		return nil, fmt.Errorf("dbotel: register connection pool metrics: %w", err)
+	}
+
+	meterProvider := opts.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	slowQueries, err := meterProvider.Meter("telemetry/dbotel").Int64Counter("db.client.slow_queries")
+	if err != nil {
+		return nil, fmt.Errorf("dbotel: build slow_queries counter: %w", err)
+	}
+
+	return &DB{DB: sqlDB, opts: opts, slowQueries: slowQueries}, nil
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx = db.before(ctx, query)
+	start := time.Now()
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	db.after(ctx, query, err, time.Since(start))
+	return rows, err
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx = db.before(ctx, query)
+	start := time.Now()
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	db.after(ctx, query, row.Err(), time.Since(start))
+	return row
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx = db.before(ctx, query)
+	start := time.Now()
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	db.after(ctx, query, err, time.Since(start))
+	return result, err
+}
+
+func (db *DB) before(ctx context.Context, query string) context.Context {
+	for _, hook := range db.opts.Hooks {
+		ctx = hook.BeforeQuery(ctx, query)
+	}
+	return ctx
+}
+
+func (db *DB) after(ctx context.Context, query string, err error, duration time.Duration) {
+	for _, hook := range db.opts.Hooks {
+		hook.AfterQuery(ctx, query, err)
+	}
+
+	if db.opts.SlowQueryThreshold <= 0 || duration < db.opts.SlowQueryThreshold {
+		return
+	}
+
+	sanitized := Sanitize(query)
+	trace.SpanFromContext(ctx).AddEvent("slow_query", trace.WithAttributes(
+		attribute.String("db.statement", sanitized),
+		attribute.Int64("db.client.slow_query.duration_ms", duration.Milliseconds()),
+	))
+	db.slowQueries.Add(ctx, 1, metric.WithAttributes(attribute.String("db.statement", sanitized)))
+}
+
+// attributesGetter records the sanitized statement as the db.statement
+// span attribute instead of the raw, literal-bearing query otelsql would
+// otherwise attach.
+func attributesGetter(_ context.Context, _ otelsql.Method, query string, _ []driver.NamedValue) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("db.statement", Sanitize(query))}
+}
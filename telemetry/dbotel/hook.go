@@ -0,0 +1,31 @@
+package dbotel
+
+import "context"
+
+// QueryHook lets callers attach their own per-query span attributes (tenant
+// id, shard, ...) without forking dbotel, mirroring the bunotel-style
+// query-hook pattern over plain database/sql.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, query string) context.Context
+	AfterQuery(ctx context.Context, query string, err error)
+}
+
+// HookFunc pairs of plain functions into a QueryHook, the same shape as
+// http.HandlerFunc elsewhere in this repo.
+type HookFunc struct {
+	Before func(ctx context.Context, query string) context.Context
+	After  func(ctx context.Context, query string, err error)
+}
+
+func (h HookFunc) BeforeQuery(ctx context.Context, query string) context.Context {
+	if h.Before == nil {
+		return ctx
+	}
+	return h.Before(ctx, query)
+}
+
+func (h HookFunc) AfterQuery(ctx context.Context, query string, err error) {
+	if h.After != nil {
+		h.After(ctx, query, err)
+	}
+}
@@ -0,0 +1,30 @@
+package dbotel
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	sqlStringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+	// sqlNumberOrPlaceholder matches either a placeholder ($1, :name) or a
+	// bare numeric literal. Go's RE2 engine has no lookbehind, so the two
+	// are matched together and told apart in Sanitize instead of trying to
+	// exclude placeholders from sqlNumberOrPlaceholder's pattern directly.
+	sqlNumberOrPlaceholder = regexp.MustCompile(`\$\d+|:\w+|\b\d+(\.\d+)?\b`)
+)
+
+// Sanitize strips literal values out of a SQL statement so it's safe to
+// record as a span attribute, keeping placeholders (`?`, `$1`, `:name`)
+// and the statement shape intact.
+func Sanitize(query string) string {
+	query = sqlStringLiteral.ReplaceAllString(query, "?")
+	query = sqlNumberOrPlaceholder.ReplaceAllStringFunc(query, func(match string) string {
+		if strings.HasPrefix(match, "$") || strings.HasPrefix(match, ":") {
+			return match
+		}
+		return "?"
+	})
+	return query
+}
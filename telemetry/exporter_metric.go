@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newMetricReader builds the sdkmetric.Reader selected by
+// opts.MetricExporter. The OTLP exporters are wrapped in a PeriodicReader;
+// Prometheus is pull-based, so newPrometheusReader returns its reader
+// directly.
+func newMetricReader(ctx context.Context, opts Options) (sdkmetric.Reader, error) {
+	switch opts.MetricExporter {
+	case "", MetricExporterOTLPGRPC:
+		grpcOpts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithInsecure())
+		}
+		exp, err := otlpmetricgrpc.New(ctx, grpcOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp), nil
+	case MetricExporterOTLPHTTP:
+		httpOpts := []otlpmetrichttp.Option{
+			otlpmetrichttp.WithEndpoint(opts.OTLPEndpoint),
+			otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+			otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+				Enabled:         opts.OTLPRetry.Enabled,
+				InitialInterval: opts.OTLPRetry.InitialInterval,
+				MaxInterval:     opts.OTLPRetry.MaxInterval,
+				MaxElapsedTime:  opts.OTLPRetry.MaxElapsedTime,
+			}),
+		}
+		if opts.OTLPInsecure {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithInsecure())
+		} else if opts.OTLPTLSConfig != nil {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithTLSClientConfig(opts.OTLPTLSConfig))
+		}
+		if opts.OTLPProxy != nil {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithProxy(otlpmetrichttp.HTTPTransportProxyFunc(opts.OTLPProxy)))
+		}
+		exp, err := otlpmetrichttp.New(ctx, httpOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return sdkmetric.NewPeriodicReader(exp), nil
+	case MetricExporterPrometheus:
+		return newPrometheusReader()
+	default:
+		return nil, fmt.Errorf("unknown metric exporter %q", opts.MetricExporter)
+	}
+}
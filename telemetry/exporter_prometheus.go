@@ -0,0 +1,13 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newPrometheusReader builds the Prometheus metric reader. The caller is
+// still responsible for serving promhttp.Handler() on PrometheusListenAddr;
+// Setup only wires the reader into the SDK meter provider.
+func newPrometheusReader() (sdkmetric.Reader, error) {
+	return prometheus.New()
+}
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newTraceExporter builds the sdktrace.SpanExporter selected by
+// opts.TraceExporter.
+func newTraceExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch opts.TraceExporter {
+	case "", TraceExporterOTLPGRPC:
+		grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, grpcOpts...)
+	case TraceExporterOTLPHTTP:
+		httpOpts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(opts.OTLPEndpoint),
+			otlptracehttp.WithCompression(otlptracehttp.GzipCompression),
+			otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+				Enabled:         opts.OTLPRetry.Enabled,
+				InitialInterval: opts.OTLPRetry.InitialInterval,
+				MaxInterval:     opts.OTLPRetry.MaxInterval,
+				MaxElapsedTime:  opts.OTLPRetry.MaxElapsedTime,
+			}),
+		}
+		if opts.OTLPInsecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		} else if opts.OTLPTLSConfig != nil {
+			httpOpts = append(httpOpts, otlptracehttp.WithTLSClientConfig(opts.OTLPTLSConfig))
+		}
+		if opts.OTLPProxy != nil {
+			httpOpts = append(httpOpts, otlptracehttp.WithProxy(otlptracehttp.HTTPTransportProxyFunc(opts.OTLPProxy)))
+		}
+		return otlptracehttp.New(ctx, httpOpts...)
+	case TraceExporterJaeger:
+		return jaeger.New(jaeger.WithCollectorEndpoint())
+	case TraceExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", opts.TraceExporter)
+	}
+}
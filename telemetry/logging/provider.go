@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ExporterKind selects the OTLP logs transport NewLoggerProvider wires up.
+type ExporterKind string
+
+const (
+	ExporterOTLPGRPC ExporterKind = "otlpgrpc"
+	ExporterOTLPHTTP ExporterKind = "otlphttp"
+)
+
+// NewLoggerProvider builds an sdklog.LoggerProvider that batches records to
+// an OTLP logs endpoint, mirroring how the trace and metric providers are
+// built in the parent telemetry package.
+func NewLoggerProvider(ctx context.Context, res *resource.Resource, kind ExporterKind, endpoint string, insecure bool) (*sdklog.LoggerProvider, error) {
+	var (
+		exporter sdklog.Exporter
+		err      error
+	)
+
+	switch kind {
+	case "", ExporterOTLPGRPC:
+		grpcOpts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint)}
+		if insecure {
+			grpcOpts = append(grpcOpts, otlploggrpc.WithInsecure())
+		}
+		exporter, err = otlploggrpc.New(ctx, grpcOpts...)
+	case ExporterOTLPHTTP:
+		httpOpts := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}
+		if insecure {
+			httpOpts = append(httpOpts, otlploghttp.WithInsecure())
+		}
+		exporter, err = otlploghttp.New(ctx, httpOpts...)
+	default:
+		return nil, fmt.Errorf("telemetry/logging: unknown exporter %q", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("telemetry/logging: build exporter: %w", err)
+	}
+
+	return sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	), nil
+}
@@ -0,0 +1,149 @@
+package telemetry
+
+import (
+	"crypto/tls"
+	"os"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+
+	"github.com/tmeire/blog-snippets/telemetry/logging"
+	"github.com/tmeire/blog-snippets/telemetry/sampling"
+)
+
+// TraceExporterKind selects which trace exporter Setup wires up.
+type TraceExporterKind string
+
+const (
+	TraceExporterOTLPGRPC TraceExporterKind = "otlpgrpc"
+	TraceExporterOTLPHTTP TraceExporterKind = "otlphttp"
+	TraceExporterJaeger   TraceExporterKind = "jaeger"
+	TraceExporterStdout   TraceExporterKind = "stdout"
+)
+
+// MetricExporterKind selects which metric reader Setup wires up.
+type MetricExporterKind string
+
+const (
+	MetricExporterOTLPGRPC   MetricExporterKind = "otlpgrpc"
+	MetricExporterOTLPHTTP   MetricExporterKind = "otlphttp"
+	MetricExporterPrometheus MetricExporterKind = "prometheus"
+)
+
+// SamplerKind selects the trace sampler Setup configures.
+type SamplerKind string
+
+const (
+	SamplerAlwaysOn     SamplerKind = "always_on"
+	SamplerParentBased  SamplerKind = "parentbased_always_on"
+	SamplerTraceIDRatio SamplerKind = "traceidratio"
+)
+
+// TailSamplingOptions configures the telemetry/sampling.TailSampler Setup
+// installs when Options.TailSampling is non-nil.
+type TailSamplingOptions struct {
+	Policies     []sampling.Policy
+	DecisionWait time.Duration
+	MaxTraces    int
+}
+
+// Options configures Setup. Zero-value Options is not usable on its own;
+// callers should start from DefaultOptions and override what they need.
+type Options struct {
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+
+	TraceExporter  TraceExporterKind
+	MetricExporter MetricExporterKind
+
+	// LogExporter selects the OTLP logs transport Setup wires up as the
+	// global LoggerProvider, or "" to leave logging unconfigured (the
+	// default, since not every example needs it). See the
+	// telemetry/logging subpackage for the slog bridge that reads from
+	// the resulting provider.
+	LogExporter logging.ExporterKind
+
+	// OTLPEndpoint is passed explicitly to the OTLP trace, metric and log
+	// exporters, so the OTEL_EXPORTER_OTLP_*_ENDPOINT env vars do not
+	// override it; DefaultOptions seeds it from OTEL_EXPORTER_OTLP_ENDPOINT.
+	OTLPEndpoint string
+	OTLPInsecure bool
+
+	// OTLPTLSConfig is only consulted by the otlphttp exporters; the grpc
+	// exporters fall back to WithInsecure/plaintext when OTLPInsecure is
+	// true and to the system cert pool otherwise.
+	OTLPTLSConfig *tls.Config
+
+	// OTLPRetry configures the retry-with-backoff loop the otlphttp
+	// exporters run around failed exports. Build it with NewRetryConfig so
+	// callers can override individual settings via RetryOption instead of
+	// constructing the struct by hand.
+	OTLPRetry RetryConfig
+
+	// OTLPProxy is consulted by the otlphttp exporters only; it's passed
+	// straight through to otlptracehttp.WithProxy/otlpmetrichttp.WithProxy.
+	// Leave nil to use the transport's own default, which takes the proxy
+	// from the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables.
+	OTLPProxy HTTPTransportProxyFunc
+
+	// PrometheusListenAddr is the address for the Prometheus metrics
+	// endpoint when MetricExporter is MetricExporterPrometheus. Setup does
+	// not read it and starts no HTTP listener; serving the endpoint is left
+	// to the caller.
+	PrometheusListenAddr string
+
+	Sampler      SamplerKind
+	SamplerRatio float64
+
+	// TailSampling, when set, replaces the head-based Sampler/SamplerRatio
+	// pair with a telemetry/sampling.TailSampler: every span is recorded
+	// locally and the keep/drop decision is made after each trace's root
+	// span ends.
+	TailSampling *TailSamplingOptions
+
+	// Views let callers override the default aggregation or attribute set
+	// of individual instruments, e.g. to narrow histogram bucket
+	// boundaries or drop a high-cardinality attribute.
+	Views []sdkmetric.View
+
+	// ExemplarFilter controls which measurements the meter provider attaches
+	// exemplars to. Defaults to the SDK's own default (exemplar.TraceBasedFilter)
+	// when left nil; pass exemplar.AlwaysOnFilter to get an exemplar on every
+	// measurement regardless of whether it's part of a sampled trace.
+	ExemplarFilter exemplar.Filter
+}
+
+// DefaultOptions returns the Options this package uses when a caller leaves
+// a field unset, seeded from the standard OTEL_* environment variables where
+// one exists.
+func DefaultOptions() Options {
+	otlpTraceKind, otlpMetricKind := TraceExporterOTLPGRPC, MetricExporterOTLPGRPC
+	if getenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc") == "http/protobuf" {
+		otlpTraceKind, otlpMetricKind = TraceExporterOTLPHTTP, MetricExporterOTLPHTTP
+	}
+
+	opts := Options{
+		ServiceName:          getenv("OTEL_SERVICE_NAME", "unnamed-service"),
+		ServiceVersion:       getenv("OTEL_SERVICE_VERSION", "0.0.0"),
+		Environment:          getenv("DEPLOYMENT_ENVIRONMENT", "dev"),
+		TraceExporter:        TraceExporterKind(getenv("OTEL_TRACES_EXPORTER", string(otlpTraceKind))),
+		MetricExporter:       MetricExporterKind(getenv("OTEL_METRICS_EXPORTER", string(otlpMetricKind))),
+		LogExporter:          logging.ExporterKind(getenv("OTEL_LOGS_EXPORTER", "")),
+		OTLPEndpoint:         getenv("OTEL_EXPORTER_OTLP_ENDPOINT", "localhost:4317"),
+		OTLPInsecure:         getenv("OTEL_EXPORTER_OTLP_INSECURE", "true") == "true",
+		OTLPRetry:            NewRetryConfig(),
+		PrometheusListenAddr: getenv("OTEL_EXPORTER_PROMETHEUS_ADDR", ":9464"),
+		Sampler:              SamplerKind(getenv("OTEL_TRACES_SAMPLER", string(SamplerParentBased))),
+		SamplerRatio:         1.0,
+	}
+	return opts
+}
+
+func getenv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryConfig bounds the exponential backoff the otlphttp exporters apply
+// to retryable export failures (e.g. 429/503 responses). Build one with
+// NewRetryConfig rather than constructing it directly.
+type RetryConfig struct {
+	Enabled         bool
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+}
+
+// RetryOption overrides a single RetryConfig setting on top of the defaults
+// NewRetryConfig starts from.
+type RetryOption func(*RetryConfig)
+
+// WithRetryDisabled turns off the otlphttp exporters' retry loop, so a
+// failed export is dropped instead of retried.
+func WithRetryDisabled() RetryOption {
+	return func(c *RetryConfig) { c.Enabled = false }
+}
+
+// WithRetryInitialInterval sets the backoff delay before the first retry.
+func WithRetryInitialInterval(d time.Duration) RetryOption {
+	return func(c *RetryConfig) { c.InitialInterval = d }
+}
+
+// WithRetryMaxInterval caps how long the backoff delay is allowed to grow
+// to between retries.
+func WithRetryMaxInterval(d time.Duration) RetryOption {
+	return func(c *RetryConfig) { c.MaxInterval = d }
+}
+
+// WithRetryMaxElapsedTime caps the total time spent retrying a single
+// export before giving up.
+func WithRetryMaxElapsedTime(d time.Duration) RetryOption {
+	return func(c *RetryConfig) { c.MaxElapsedTime = d }
+}
+
+// NewRetryConfig builds a RetryConfig matching the otlphttp exporters' own
+// built-in defaults, with opts applied on top.
+func NewRetryConfig(opts ...RetryOption) RetryConfig {
+	c := RetryConfig{
+		Enabled:         true,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// HTTPTransportProxyFunc resolves which URL to use as proxy for a given
+// request, mirroring otlptracehttp.HTTPTransportProxyFunc and
+// otlpmetrichttp.HTTPTransportProxyFunc so Options.OTLPProxy can configure
+// both exporters from a single field.
+type HTTPTransportProxyFunc func(*http.Request) (*url.URL, error)
+
+// ProxyURL returns an HTTPTransportProxyFunc that always routes through u,
+// for the common case of a single fixed proxy rather than one resolved per
+// request (see http.ProxyURL).
+func ProxyURL(u *url.URL) HTTPTransportProxyFunc {
+	return HTTPTransportProxyFunc(http.ProxyURL(u))
+}
@@ -0,0 +1,61 @@
+package sampling
+
+import (
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Policy decides whether a buffered trace should be kept once its root
+// span has ended. Policies are evaluated in order; the first one that
+// returns decided=true wins.
+type Policy interface {
+	Name() string
+	Decide(tr *Trace) (decided, keep bool)
+}
+
+// ErrorPolicy keeps any trace containing a span with an error status or an
+// exception event.
+type ErrorPolicy struct{}
+
+func (ErrorPolicy) Name() string { return "error" }
+
+func (ErrorPolicy) Decide(tr *Trace) (decided, keep bool) {
+	if tr.HasError {
+		return true, true
+	}
+	return false, false
+}
+
+// LatencyPolicy keeps any trace whose root span ran longer than Threshold.
+type LatencyPolicy struct {
+	Threshold time.Duration
+}
+
+func (LatencyPolicy) Name() string { return "latency" }
+
+func (p LatencyPolicy) Decide(tr *Trace) (decided, keep bool) {
+	if tr.RootEnded && tr.RootDuration > p.Threshold {
+		return true, true
+	}
+	return false, false
+}
+
+// ProbabilisticPolicy keeps a trace with probability Rate. It always
+// decides, so it's meant to be the last policy in the chain.
+type ProbabilisticPolicy struct {
+	Rate float64
+}
+
+func (ProbabilisticPolicy) Name() string { return "probabilistic" }
+
+func (p ProbabilisticPolicy) Decide(*Trace) (decided, keep bool) {
+	return true, rand.Float64() < p.Rate
+}
+
+// hasErrorStatus reports whether a span recorded an error status, used by
+// TailSampler while buffering spans for ErrorPolicy to evaluate later.
+func hasErrorStatus(code codes.Code) bool {
+	return code == codes.Error
+}
@@ -0,0 +1,295 @@
+// Package sampling implements tail-based sampling for the OpenTelemetry Go
+// SDK as a plain sdktrace.SpanProcessor, so it drops in anywhere
+// sdktrace.WithBatcher would normally be used. Every example in this repo
+// uses AlwaysSample(), which is fine for a demo but means a production
+// user pays to export 100% of traces whether or not anything interesting
+// happened in them; TailSampler lets them keep every error and every slow
+// trace while only sampling a fraction of the rest.
+package sampling
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace is the per-trace state TailSampler buffers while waiting for the
+// root span to end (or for the decision-wait timeout to expire).
+type Trace struct {
+	Spans        []sdktrace.ReadOnlySpan
+	FirstSeen    time.Time
+	RootEnded    bool
+	RootDuration time.Duration
+	HasError     bool
+}
+
+const (
+	defaultMaxTraces    = 10_000
+	defaultDecisionWait = 10 * time.Second
+)
+
+// Option configures a TailSampler.
+type Option func(*TailSampler)
+
+// WithPolicies sets the ordered list of policies TailSampler applies to a
+// trace once it's ready to be decided. The default is an empty chain: no
+// policy decides, so every trace is dropped and counted under the policy
+// name "none" - callers should always supply their own chain.
+func WithPolicies(policies ...Policy) Option {
+	return func(s *TailSampler) { s.policies = policies }
+}
+
+// WithDecisionWait bounds how long TailSampler buffers a trace that never
+// sees its root span end (e.g. the root lives in a service that crashed).
+// Such traces are decided against whatever spans did arrive.
+func WithDecisionWait(d time.Duration) Option {
+	return func(s *TailSampler) { s.decisionWait = d }
+}
+
+// WithMaxTraces bounds the number of in-flight traces TailSampler buffers
+// at once. Once the limit is reached, the oldest buffered trace is decided
+// early to make room, same as an LRU cache eviction.
+func WithMaxTraces(n int) Option {
+	return func(s *TailSampler) { s.maxTraces = n }
+}
+
+// WithMeterProvider overrides the MeterProvider used for the
+// sampler.traces.sampled/dropped instruments. Defaults to
+// otel.GetMeterProvider().
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(s *TailSampler) { s.meterProvider = mp }
+}
+
+// TailSampler buffers spans per trace ID and, once a trace is decided,
+// either forwards its spans to next or drops them. It implements
+// sdktrace.SpanProcessor so it can be installed via
+// sdktrace.WithSpanProcessor in place of sdktrace.WithBatcher; the
+// TracerProvider must additionally be configured with
+// sdktrace.AlwaysSample() so every span reaches TailSampler - sampling out
+// spans before they're buffered would defeat the point.
+type TailSampler struct {
+	next sdktrace.SpanProcessor
+
+	policies     []Policy
+	decisionWait time.Duration
+	maxTraces    int
+
+	mu      sync.Mutex
+	traces  map[trace.TraceID]*Trace
+	order   *list.List // list.Element.Value is trace.TraceID, front = oldest
+	elemOf  map[trace.TraceID]*list.Element
+	closeCh chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+
+	meterProvider metric.MeterProvider
+	sampled       metric.Int64Counter
+	dropped       metric.Int64Counter
+}
+
+// NewTailSampler builds a TailSampler that forwards kept traces to next.
+func NewTailSampler(next sdktrace.SpanProcessor, opts ...Option) *TailSampler {
+	s := &TailSampler{
+		next:         next,
+		decisionWait: defaultDecisionWait,
+		maxTraces:    defaultMaxTraces,
+		traces:       make(map[trace.TraceID]*Trace),
+		order:        list.New(),
+		elemOf:       make(map[trace.TraceID]*list.Element),
+		closeCh:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.meterProvider == nil {
+		s.meterProvider = otel.GetMeterProvider()
+	}
+	meter := s.meterProvider.Meter("telemetry/sampling")
+	s.sampled, _ = meter.Int64Counter("sampler.traces.sampled")
+	s.dropped, _ = meter.Int64Counter("sampler.traces.dropped")
+
+	s.wg.Add(1)
+	go s.sweepExpiredTraces()
+
+	return s
+}
+
+func (s *TailSampler) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (s *TailSampler) OnEnd(span sdktrace.ReadOnlySpan) {
+	traceID := span.SpanContext().TraceID()
+
+	s.mu.Lock()
+	tr, ok := s.traces[traceID]
+	var evicted *Trace
+	if !ok {
+		tr = &Trace{FirstSeen: time.Now()}
+		s.traces[traceID] = tr
+		s.elemOf[traceID] = s.order.PushBack(traceID)
+		evicted = s.evictOldestLocked()
+	} else if elem, ok := s.elemOf[traceID]; ok {
+		s.order.MoveToBack(elem)
+	}
+	tr.Spans = append(tr.Spans, span)
+	if !span.Parent().IsValid() {
+		tr.RootEnded = true
+		tr.RootDuration = span.EndTime().Sub(span.StartTime())
+	}
+	if hasErrorStatus(span.Status().Code) {
+		tr.HasError = true
+	}
+	for _, ev := range span.Events() {
+		if ev.Name == "exception" {
+			tr.HasError = true
+		}
+	}
+	rootEnded := tr.RootEnded
+	s.mu.Unlock()
+
+	// finalize runs the policy chain, so an evicted trace that's seen an
+	// error still gets forwarded instead of silently dropped; it's called
+	// here, outside s.mu, because decide (which finalize also backs) would
+	// deadlock re-locking a mutex this goroutine already holds.
+	if evicted != nil {
+		s.finalize(evicted)
+	}
+	if rootEnded {
+		s.decide(traceID)
+	}
+}
+
+// evictOldestLocked removes the oldest buffered trace once maxTraces is
+// exceeded, same bound an LRU cache enforces, and returns it so the caller
+// can run it through the policy chain after releasing s.mu. Returns nil if
+// nothing needed evicting. Callers must hold s.mu.
+func (s *TailSampler) evictOldestLocked() *Trace {
+	if s.maxTraces <= 0 || len(s.traces) <= s.maxTraces {
+		return nil
+	}
+	front := s.order.Front()
+	if front == nil {
+		return nil
+	}
+	oldest := front.Value.(trace.TraceID)
+	s.order.Remove(front)
+	delete(s.elemOf, oldest)
+	tr := s.traces[oldest]
+	delete(s.traces, oldest)
+	return tr
+}
+
+// sweepExpiredTraces force-decides traces whose root span never arrived
+// within decisionWait, so a crashed or misconfigured caller can't pin
+// memory forever.
+func (s *TailSampler) sweepExpiredTraces() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case <-ticker.C:
+			s.sweepOnce()
+		}
+	}
+}
+
+func (s *TailSampler) sweepOnce() {
+	now := time.Now()
+
+	s.mu.Lock()
+	var expired []trace.TraceID
+	for id, tr := range s.traces {
+		if now.Sub(tr.FirstSeen) >= s.decisionWait {
+			expired = append(expired, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range expired {
+		s.decide(id)
+	}
+}
+
+// decide removes a trace from the buffer and applies the configured
+// policies to it, forwarding its spans to next if kept.
+func (s *TailSampler) decide(traceID trace.TraceID) {
+	s.mu.Lock()
+	tr, ok := s.traces[traceID]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.traces, traceID)
+	if elem, ok := s.elemOf[traceID]; ok {
+		s.order.Remove(elem)
+		delete(s.elemOf, traceID)
+	}
+	s.mu.Unlock()
+
+	s.finalize(tr)
+}
+
+// finalize applies the configured policies to tr and either forwards its
+// spans to next or counts it as dropped. tr must already be removed from
+// s.traces/s.order/s.elemOf; callers must not hold s.mu.
+func (s *TailSampler) finalize(tr *Trace) {
+	policyName, keep := s.applyPolicies(tr)
+
+	ctx := context.Background()
+	attrs := metric.WithAttributes(attribute.String("policy", policyName))
+	if keep {
+		s.sampled.Add(ctx, 1, attrs)
+		for _, span := range tr.Spans {
+			s.next.OnEnd(span)
+		}
+	} else {
+		s.dropped.Add(ctx, 1, attrs)
+	}
+}
+
+func (s *TailSampler) applyPolicies(tr *Trace) (policyName string, keep bool) {
+	for _, policy := range s.policies {
+		if decided, keep := policy.Decide(tr); decided {
+			return policy.Name(), keep
+		}
+	}
+	return "none", false
+}
+
+func (s *TailSampler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	pending := make([]trace.TraceID, 0, len(s.traces))
+	for id := range s.traces {
+		pending = append(pending, id)
+	}
+	s.mu.Unlock()
+
+	close(s.closeCh)
+	s.wg.Wait()
+
+	for _, id := range pending {
+		s.decide(id)
+	}
+
+	return s.next.Shutdown(ctx)
+}
+
+func (s *TailSampler) ForceFlush(ctx context.Context) error {
+	return s.next.ForceFlush(ctx)
+}
@@ -0,0 +1,148 @@
+// Package telemetry is a shared OpenTelemetry bootstrap for the example
+// services in this repo. It collapses the initTracer/setupOpenTelemetry
+// boilerplate that used to be copy-pasted into every main.go down to a
+// single Setup call, while still letting callers opt into the exporter,
+// sampler and metric reader that fit their example.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	logglobal "go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/tmeire/blog-snippets/telemetry/logging"
+	"github.com/tmeire/blog-snippets/telemetry/sampling"
+)
+
+// Shutdown flushes and closes every provider Setup registered. Callers
+// should defer it with a short-lived context, mirroring the cleanup
+// closures the individual examples used to return.
+type Shutdown func(ctx context.Context) error
+
+// Setup builds the resource, trace provider, meter provider and
+// propagator described by opts, installs them as the global providers via
+// otel.SetTracerProvider/otel.SetMeterProvider/otel.SetTextMapPropagator,
+// and returns a Shutdown that tears all of them down together.
+func Setup(ctx context.Context, opts Options) (Shutdown, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.ServiceVersion),
+		attribute.String("deployment.environment", opts.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: merge resource: %w", err)
+	}
+
+	traceExporter, err := newTraceExporter(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: build trace exporter: %w", err)
+	}
+
+	var tp *sdktrace.TracerProvider
+	if opts.TailSampling != nil {
+		batcher := sdktrace.NewBatchSpanProcessor(traceExporter)
+		tailOpts := []sampling.Option{sampling.WithPolicies(opts.TailSampling.Policies...)}
+		if opts.TailSampling.DecisionWait > 0 {
+			tailOpts = append(tailOpts, sampling.WithDecisionWait(opts.TailSampling.DecisionWait))
+		}
+		if opts.TailSampling.MaxTraces > 0 {
+			tailOpts = append(tailOpts, sampling.WithMaxTraces(opts.TailSampling.MaxTraces))
+		}
+
+		// Tail sampling needs every span recorded locally before it can
+		// decide whether a trace is worth keeping, so the head sampler
+		// must stay AlwaysSample regardless of opts.Sampler.
+		tp = sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithResource(res),
+			sdktrace.WithSpanProcessor(sampling.NewTailSampler(batcher, tailOpts...)),
+		)
+	} else {
+		sampler, err := newSampler(opts)
+		if err != nil {
+			return nil, fmt.Errorf("telemetry: build sampler: %w", err)
+		}
+		tp = sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sampler),
+			sdktrace.WithResource(res),
+			sdktrace.WithBatcher(traceExporter),
+		)
+	}
+	otel.SetTracerProvider(tp)
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	metricReader, err := newMetricReader(ctx, opts)
+	if err != nil {
+		_ = tp.Shutdown(ctx)
+		return nil, fmt.Errorf("telemetry: build metric reader: %w", err)
+	}
+
+	mpOpts := []sdkmetric.Option{
+		sdkmetric.WithReader(metricReader),
+		sdkmetric.WithResource(res),
+	}
+	for _, view := range opts.Views {
+		mpOpts = append(mpOpts, sdkmetric.WithView(view))
+	}
+	if opts.ExemplarFilter != nil {
+		mpOpts = append(mpOpts, sdkmetric.WithExemplarFilter(opts.ExemplarFilter))
+	}
+
+	mp := sdkmetric.NewMeterProvider(mpOpts...)
+	otel.SetMeterProvider(mp)
+
+	var lp *sdklog.LoggerProvider
+	if opts.LogExporter != "" {
+		lp, err = logging.NewLoggerProvider(ctx, res, opts.LogExporter, opts.OTLPEndpoint, opts.OTLPInsecure)
+		if err != nil {
+			_ = tp.Shutdown(ctx)
+			_ = mp.Shutdown(ctx)
+			return nil, fmt.Errorf("telemetry: build logger provider: %w", err)
+		}
+		logglobal.SetLoggerProvider(lp)
+	}
+
+	return func(ctx context.Context) error {
+		var err error
+		if tpErr := tp.Shutdown(ctx); tpErr != nil {
+			err = errors.Join(err, fmt.Errorf("shut down tracer provider: %w", tpErr))
+		}
+		if mpErr := mp.Shutdown(ctx); mpErr != nil {
+			err = errors.Join(err, fmt.Errorf("shut down meter provider: %w", mpErr))
+		}
+		if lp != nil {
+			if lpErr := lp.Shutdown(ctx); lpErr != nil {
+				err = errors.Join(err, fmt.Errorf("shut down logger provider: %w", lpErr))
+			}
+		}
+		return err
+	}, nil
+}
+
+func newSampler(opts Options) (sdktrace.Sampler, error) {
+	switch opts.Sampler {
+	case "", SamplerParentBased:
+		return sdktrace.ParentBased(sdktrace.AlwaysSample()), nil
+	case SamplerAlwaysOn:
+		return sdktrace.AlwaysSample(), nil
+	case SamplerTraceIDRatio:
+		return sdktrace.TraceIDRatioBased(opts.SamplerRatio), nil
+	default:
+		return nil, fmt.Errorf("unknown sampler %q", opts.Sampler)
+	}
+}
@@ -2,27 +2,23 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"log"
 	"net/http"
 	"time"
 
-	"github.com/XSAM/otelsql"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/tmeire/blog-snippets/telemetry"
+	"github.com/tmeire/blog-snippets/telemetry/dbotel"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-var db *sql.DB
+var db *dbotel.DB
 
 type User struct {
 	ID    int    `json:"id"`
@@ -30,71 +26,14 @@ type User struct {
 	Email string `json:"email"`
 }
 
-func initTracer() func() {
-	// Create a resource describing the service
-	res, err := resource.New(context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String("user-service"),
-			semconv.ServiceVersionKey.String("1.0.0"),
-		),
-	)
-	if err != nil {
-		log.Fatalf("Failed to create resource: %v", err)
-	}
-
-	// Set up a connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Create an exporter to the collector
-	exporter, err := otlptracegrpc.New(ctx,
-		otlptracegrpc.WithEndpoint("localhost:4317"),
-		otlptracegrpc.WithInsecure(),
-	)
-	if err != nil {
-		log.Fatalf("Failed to create exporter: %v", err)
-	}
-
-	// Create a trace provider with a batch span processor
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(res),
-		sdktrace.WithBatcher(exporter),
-	)
-	otel.SetTracerProvider(tp)
-
-	// Set up propagation
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
-	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := tp.Shutdown(ctx); err != nil {
-			log.Fatalf("Failed to shut down tracer provider: %v", err)
-		}
-	}
-}
-
 func initDB() {
-	// Register the sqlite driver with otelsql
-	driverName, err := otelsql.Register("sqlite3",
-		otelsql.WithAttributes(semconv.DBSystemSqlite),
-		otelsql.WithSpanOptions(otelsql.SpanOptions{
-			Ping:     true,
-			RowsNext: true,
-		}),
-	)
+	var err error
+	db, err = dbotel.Open("sqlite3", "users.db", dbotel.Options{
+		DBSystem:           semconv.DBSystemSqlite,
+		SlowQueryThreshold: 100 * time.Millisecond,
+	})
 	if err != nil {
-		log.Fatalf("Failed to register otelsql driver: %v", err)
-	}
-
-	// Open a database connection using the instrumented driver
-	db, err = sql.Open(driverName, "users.db")
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to open database: %v", err)
 	}
 
 	if err = db.Ping(); err != nil {
@@ -146,9 +85,21 @@ func getUserFromDB(ctx context.Context, userID string) (*User, error) {
 }
 
 func main() {
-	// Initialize tracer
-	cleanup := initTracer()
-	defer cleanup()
+	ctx := context.Background()
+
+	opts := telemetry.DefaultOptions()
+	opts.ServiceName = "user-service"
+	opts.ServiceVersion = "1.0.0"
+
+	shutdown, err := telemetry.Setup(ctx, opts)
+	if err != nil {
+		log.Fatalf("Failed to set up telemetry: %v", err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Fatalf("Failed to shut down telemetry: %v", err)
+		}
+	}()
 
 	// Initialize database
 	initDB()
@@ -3,64 +3,26 @@ package main
 import (
 	"context"
 	"log"
-	"time"
 
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"github.com/tmeire/blog-snippets/telemetry"
 )
 
-func initTracer() func() {
-	// Create a resource describing the service
-	res, err := resource.New(context.Background(),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String("my-service"),
-			semconv.ServiceVersionKey.String("1.0.0"),
-		),
-	)
-	if err != nil {
-		log.Fatalf("Failed to create resource: %v", err)
-	}
+func main() {
+	ctx := context.Background()
 
-	// Set up a connection to the collector
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	opts := telemetry.DefaultOptions()
+	opts.ServiceName = "my-service"
+	opts.ServiceVersion = "1.0.0"
 
-	// Create an exporter that connects to the collector
-	exporter, err := otlptracegrpc.New(ctx)
+	shutdown, err := telemetry.Setup(ctx, opts)
 	if err != nil {
-		log.Fatalf("Failed to create exporter: %v", err)
+		log.Fatalf("Failed to set up telemetry: %v", err)
 	}
-
-	// Create a trace provider
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),,
-		sdktrace.WithResource(res),
-		sdktrace.WithBatcher(exporter),
-	)
-	otel.SetTracerProvider(tp)
-
-	// Set up propagation
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
-	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		if err := tp.Shutdown(ctx); err != nil {
-			log.Fatalf("Failed to shut down tracer provider: %v", err)
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Fatalf("Failed to shut down telemetry: %v", err)
 		}
-	}
-}
-
-func main() {
-	cleanup := initTracer()
-	defer cleanup()
+	}()
 
 	// Your application code here
 }